@@ -0,0 +1,118 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/lib/cid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// aclKey is the reserved ledger key under which the list of MSP IDs allowed
+// to mutate state is stored. read and getStateByRange do not filter it out, so
+// it can be read back like any other key, and chaincode authors should avoid
+// reusing it for application data.
+const aclKey = "~acl_allowed_msps"
+
+// roleAttr is the client identity attribute checked for the admin bypass
+// below, following the standard Fabric CA enrollment convention of
+// `--id.attrs 'role=admin:ecert'` -- i.e. the attribute is named "role" and
+// its value, not its mere presence, is what grants the exemption.
+const roleAttr = "role"
+
+// adminRole is the roleAttr value that exempts its holder from the
+// allowed-MSP check below.
+const adminRole = "admin"
+
+// acl is the access-control list stored under aclKey.
+type acl struct {
+	AllowedMSPs []string `json:"allowedMSPs"`
+}
+
+// initACL expects args: [mspID, mspID, ...] and stores the list of MSP IDs
+// permitted to perform write operations. Calling it with no args clears the
+// restriction, so authorize becomes a no-op again.
+func (t *SimpleChaincode) initACL(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	list := acl{AllowedMSPs: args}
+
+	aclBytes, err := json.Marshal(list)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if err := stub.PutState(aclKey, aclBytes); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// loadACL reads the configured ACL, if any. A missing aclKey means no
+// restriction has been configured and results in a zero-value acl.
+func loadACL(stub shim.ChaincodeStubInterface) (*acl, error) {
+	aclBytes, err := stub.GetState(aclKey)
+	if err != nil {
+		return nil, err
+	}
+	if aclBytes == nil {
+		return &acl{}, nil
+	}
+
+	list := &acl{}
+	if err := json.Unmarshal(aclBytes, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// authorize rejects the call unless the submitter's MSP ID is in the
+// configured allow-list, or the submitter carries the role=admin attribute.
+// When no ACL has been configured via initACL, every caller is permitted so
+// existing deployments keep working unchanged.
+func authorize(stub shim.ChaincodeStubInterface, action string) error {
+	list, err := loadACL(stub)
+	if err != nil {
+		return err
+	}
+	if len(list.AllowedMSPs) == 0 {
+		return nil
+	}
+
+	role, _, err := cid.GetAttributeValue(stub, roleAttr)
+	if err != nil {
+		return err
+	}
+	if role == adminRole {
+		return nil
+	}
+
+	mspID, err := cid.GetMSPID(stub)
+	if err != nil {
+		return err
+	}
+
+	for _, allowed := range list.AllowedMSPs {
+		if allowed == mspID {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("caller from MSP %s is not authorized to %s", mspID, action)
+}
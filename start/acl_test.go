@@ -0,0 +1,162 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/msp"
+)
+
+// fabricAttrOID is the ASN.1 extension Fabric CA embeds enrollment
+// attributes under, read back by cid.GetAttributeValue.
+var fabricAttrOID = asn1.ObjectIdentifier{1, 2, 3, 4, 5, 6, 7, 8, 1}
+
+// certWithAttrs generates a throwaway self-signed certificate carrying the
+// given Fabric CA-style enrollment attributes, PEM-encoded, for use as a
+// MockCreator identity's IdBytes.
+func certWithAttrs(t *testing.T, attrs map[string]string) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("could not generate test key: %s", err)
+	}
+
+	attrsJSON, err := json.Marshal(struct {
+		Attrs map[string]string `json:"attrs"`
+	}{Attrs: attrs})
+	if err != nil {
+		t.Fatalf("could not marshal test attributes: %s", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    time.Unix(0, 0),
+		NotAfter:     time.Unix(0, 0).Add(24 * time.Hour),
+		ExtraExtensions: []pkix.Extension{
+			{Id: fabricAttrOID, Critical: false, Value: attrsJSON},
+		},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("could not create test certificate: %s", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+}
+
+// serializedIdentity builds the bytes MockCreator expects: a marshaled
+// msp.SerializedIdentity carrying the MSP ID and a generated certificate with
+// no enrollment attributes. authorize parses that certificate through cid, so
+// it has to be a real, parseable X.509 certificate.
+func serializedIdentity(t *testing.T, mspID string) []byte {
+	id := &msp.SerializedIdentity{Mspid: mspID, IdBytes: certWithAttrs(t, nil)}
+	idBytes, err := proto.Marshal(id)
+	if err != nil {
+		t.Fatalf("could not marshal serialized identity: %s", err)
+	}
+	return idBytes
+}
+
+func TestAuthorize_NoACLConfigured(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	// No initACL call was made, so writes should succeed for any caller.
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+}
+
+func TestAuthorize_RejectsUnlistedMSP(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	res := stub.MockInit("1", [][]byte{[]byte("initACL"), []byte("Org1MSP")})
+	if res.Status != shim.OK {
+		t.Fatalf("initACL failed: %s", res.Message)
+	}
+
+	stub.MockCreator("Org2MSP", serializedIdentity(t, "Org2MSP"))
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+}
+
+func TestAuthorize_AllowsListedMSP(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	res := stub.MockInit("1", [][]byte{[]byte("initACL"), []byte("Org1MSP")})
+	if res.Status != shim.OK {
+		t.Fatalf("initACL failed: %s", res.Message)
+	}
+
+	stub.MockCreator("Org1MSP", serializedIdentity(t, "Org1MSP"))
+
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+}
+
+func TestAuthorize_RoleAdminBypassesUnlistedMSP(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	res := stub.MockInit("1", [][]byte{[]byte("initACL"), []byte("Org1MSP")})
+	if res.Status != shim.OK {
+		t.Fatalf("initACL failed: %s", res.Message)
+	}
+
+	cert := certWithAttrs(t, map[string]string{"role": "admin"})
+	id := &msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: cert}
+	idBytes, err := proto.Marshal(id)
+	if err != nil {
+		t.Fatalf("could not marshal serialized identity: %s", err)
+	}
+	stub.MockCreator("Org2MSP", idBytes)
+
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+}
+
+func TestAuthorize_NonAdminRoleStillRejected(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	res := stub.MockInit("1", [][]byte{[]byte("initACL"), []byte("Org1MSP")})
+	if res.Status != shim.OK {
+		t.Fatalf("initACL failed: %s", res.Message)
+	}
+
+	cert := certWithAttrs(t, map[string]string{"role": "auditor"})
+	id := &msp.SerializedIdentity{Mspid: "Org2MSP", IdBytes: cert}
+	idBytes, err := proto.Marshal(id)
+	if err != nil {
+		t.Fatalf("could not marshal serialized identity: %s", err)
+	}
+	stub.MockCreator("Org2MSP", idBytes)
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+}
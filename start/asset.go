@@ -0,0 +1,232 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// assetIndex is the name of the secondary owner~id composite key index used
+// to look up assets by owner without scanning the whole key space.
+const assetIndex = "owner~id"
+
+// Asset is a simple example of the kind of record this chaincode manages
+// beyond the raw hello_world key/value slot -- a real piece of state with an
+// owner, a value and a creation time.
+type Asset struct {
+	ID        string `json:"id"`
+	Owner     string `json:"owner"`
+	Value     string `json:"value"`
+	CreatedAt string `json:"createdAt"`
+}
+
+// putAsset marshals and stores an asset, keeping the owner~id composite key
+// index in sync so it can be queried back with queryAssetsByOwner.
+func putAsset(stub shim.ChaincodeStubInterface, asset *Asset) error {
+	assetBytes, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+
+	if err := stub.PutState(asset.ID, assetBytes); err != nil {
+		return err
+	}
+
+	indexKey, err := stub.CreateCompositeKey(assetIndex, []string{asset.Owner, asset.ID})
+	if err != nil {
+		return err
+	}
+
+	// The composite key's value is never read, only its existence -- store
+	// an empty byte so the key shows up in a partial-key scan.
+	return stub.PutState(indexKey, []byte{0x00})
+}
+
+// getAsset loads and unmarshals the asset stored under id, failing if it does
+// not exist.
+func getAsset(stub shim.ChaincodeStubInterface, id string) (*Asset, error) {
+	assetBytes, err := stub.GetState(id)
+	if err != nil {
+		return nil, err
+	}
+	if assetBytes == nil {
+		return nil, fmt.Errorf("asset %s does not exist", id)
+	}
+
+	asset := &Asset{}
+	if err := json.Unmarshal(assetBytes, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// deleteAssetIndex removes the owner~id composite key for asset so it no
+// longer shows up in a queryAssetsByOwner scan.
+func deleteAssetIndex(stub shim.ChaincodeStubInterface, asset *Asset) error {
+	indexKey, err := stub.CreateCompositeKey(assetIndex, []string{asset.Owner, asset.ID})
+	if err != nil {
+		return err
+	}
+	return stub.DelState(indexKey)
+}
+
+// createAsset expects args: [id, owner, value, createdAt]
+func (t *SimpleChaincode) createAsset(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 4 {
+		return shim.Error("Incorrect number of arguments. Expecting 4: id, owner, value, createdAt")
+	}
+
+	if err := authorize(stub, "write"); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	id := args[0]
+	if existing, err := stub.GetState(id); err != nil {
+		return shim.Error(err.Error())
+	} else if existing != nil {
+		return shim.Error("asset " + id + " already exists")
+	}
+
+	asset := &Asset{ID: id, Owner: args[1], Value: args[2], CreatedAt: args[3]}
+	if err := putAsset(stub, asset); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// updateAsset expects args: [id, value]
+func (t *SimpleChaincode) updateAsset(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return shim.Error("Incorrect number of arguments. Expecting 2: id, value")
+	}
+
+	if err := authorize(stub, "write"); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	asset, err := getAsset(stub, args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	asset.Value = args[1]
+	if err := putAsset(stub, asset); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// transferAsset expects args: [id, newOwner]
+func (t *SimpleChaincode) transferAsset(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 {
+		return shim.Error("Incorrect number of arguments. Expecting 2: id, newOwner")
+	}
+
+	if err := authorize(stub, "write"); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	asset, err := getAsset(stub, args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if err := deleteAssetIndex(stub, asset); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	asset.Owner = args[1]
+	if err := putAsset(stub, asset); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// deleteAsset expects args: [id]
+func (t *SimpleChaincode) deleteAsset(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1: id")
+	}
+
+	if err := authorize(stub, "write"); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	asset, err := getAsset(stub, args[0])
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if err := deleteAssetIndex(stub, asset); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	if err := stub.DelState(asset.ID); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// queryAssetsByOwner expects args: [owner] and returns a JSON array of the
+// assets owned by owner, found via the owner~id composite key index.
+func (t *SimpleChaincode) queryAssetsByOwner(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1: owner")
+	}
+	owner := args[0]
+
+	iterator, err := stub.GetStateByPartialCompositeKey(assetIndex, []string{owner})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer iterator.Close()
+
+	assets := []*Asset{}
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		_, parts, err := stub.SplitCompositeKey(kv.Key)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		id := parts[1]
+
+		asset, err := getAsset(stub, id)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		assets = append(assets, asset)
+	}
+
+	assetsBytes, err := json.Marshal(assets)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(assetsBytes)
+}
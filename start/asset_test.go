@@ -0,0 +1,106 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+func TestCreateAndQueryAssetsByOwner(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset2"), []byte("alice"), []byte("200"), []byte("2020-01-02")})
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset3"), []byte("bob"), []byte("300"), []byte("2020-01-03")})
+
+	payload := checkInvoke(t, stub, [][]byte{[]byte("queryAssetsByOwner"), []byte("alice")})
+
+	var assets []*Asset
+	if err := json.Unmarshal(payload, &assets); err != nil {
+		t.Fatalf("could not unmarshal query result: %s", err)
+	}
+	if len(assets) != 2 {
+		t.Fatalf("expected 2 assets owned by alice, got %d", len(assets))
+	}
+}
+
+func TestCreateAssetDuplicate(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+	checkInvokeFailure(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+}
+
+func TestUpdateAsset(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+	checkInvoke(t, stub, [][]byte{[]byte("updateAsset"), []byte("asset1"), []byte("150")})
+
+	asset := &Asset{}
+	if err := json.Unmarshal(stub.State["asset1"], asset); err != nil {
+		t.Fatalf("could not unmarshal asset1: %s", err)
+	}
+	if asset.Value != "150" {
+		t.Fatalf("expected updated value 150, got %s", asset.Value)
+	}
+}
+
+func TestTransferAsset(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+	checkInvoke(t, stub, [][]byte{[]byte("transferAsset"), []byte("asset1"), []byte("bob")})
+
+	alicePayload := checkInvoke(t, stub, [][]byte{[]byte("queryAssetsByOwner"), []byte("alice")})
+	var aliceAssets []*Asset
+	json.Unmarshal(alicePayload, &aliceAssets)
+	if len(aliceAssets) != 0 {
+		t.Fatalf("expected alice to own 0 assets after transfer, got %d", len(aliceAssets))
+	}
+
+	bobPayload := checkInvoke(t, stub, [][]byte{[]byte("queryAssetsByOwner"), []byte("bob")})
+	var bobAssets []*Asset
+	json.Unmarshal(bobPayload, &bobAssets)
+	if len(bobAssets) != 1 {
+		t.Fatalf("expected bob to own 1 asset after transfer, got %d", len(bobAssets))
+	}
+}
+
+func TestDeleteAsset(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("createAsset"), []byte("asset1"), []byte("alice"), []byte("100"), []byte("2020-01-01")})
+	checkInvoke(t, stub, [][]byte{[]byte("deleteAsset"), []byte("asset1")})
+
+	payload := checkInvoke(t, stub, [][]byte{[]byte("queryAssetsByOwner"), []byte("alice")})
+	var assets []*Asset
+	json.Unmarshal(payload, &assets)
+	if len(assets) != 0 {
+		t.Fatalf("expected 0 assets owned by alice after delete, got %d", len(assets))
+	}
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("deleteAsset"), []byte("asset1")})
+}
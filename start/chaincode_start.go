@@ -17,9 +17,10 @@ limitations under the License.
 package main
 
 import (
-	"errors" // standard go error format.
+	"encoding/json"
 	"fmt" // contains Println for debugging/logging.
 	"github.com/hyperledger/fabric/core/chaincode/shim" // contains the definition for the chaincode interface and the chaincode stub, which you will need to interact with the ledger.
+	pb "github.com/hyperledger/fabric/protos/peer" // response type returned by Init/Invoke on the modern shim API.
 )
 
 // SimpleChaincode example simple Chaincode implementation
@@ -36,102 +37,124 @@ func main() {
 	}
 }
 
-// Init is called when you first deploy your chaincode. As the name implies, this function 
-// should be used to do any initialization your chaincode needs. 
+// Init is called when you first deploy your chaincode. As the name implies, this function
+// should be used to do any initialization your chaincode needs.
 // In our example, we use Init to configure the initial state of a single key/value pair on the ledger
 // Init resets all the things
-func (t *SimpleChaincode) Init(stub shim.ChaincodeStubInterface, function string, args []string) ([]byte, error) {
+func (t *SimpleChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
+
+	if function == "initACL" {
+		return t.initACL(stub, args)
+	}
+
 	if len(args) != 1 {
-		return nil, errors.New("Incorrect number of arguments. Expecting 1")
+		return shim.Error("Incorrect number of arguments. Expecting 1")
 	}
 
     // Stores the first element in the args argument to the key "hello_world".
-    // This is done by using the stub function stub.PutState. 
-    // The function interprets the first argument sent in the deployment request as the value to be stored under the key 'hello_world' 
-    // in the ledger. All will be explained after we finish implementing the chaincode interface. 
+    // This is done by using the stub function stub.PutState.
+    // The function interprets the first argument sent in the deployment request as the value to be stored under the key 'hello_world'
+    // in the ledger. All will be explained after we finish implementing the chaincode interface.
 	err := stub.PutState("hello_world", []byte(args[0]))
 	if err != nil {
-		return nil, err
+		return shim.Error(err.Error())
 	}
 
-	return nil, nil
+	return shim.Success(nil)
 }
 
-// Invoke is called when you want to call chaincode functions to do real work. 
+// Invoke is called when you want to call chaincode functions to do real work.
 // Invocations will be captured as a transactions, which get grouped into blocks on the chain.
 // Invoke is our entry point to invoke a chaincode function
-// When you need to update the ledger, you will do so by invoking your chaincode. 
-// The structure of Invoke is simple. It receives a function and an array of arguments. 
-// Based on what function was passed in through the function parameter in the invoke request, 
+// When you need to update the ledger, you will do so by invoking your chaincode.
+// The structure of Invoke is simple. It receives a function and an array of arguments.
+// Based on what function was passed in through the function parameter in the invoke request,
 // Invoke will either call a helper function or return an error.
-
-func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface, function string, args []string) ([]byte, error) {
+// Reads no longer get their own entry point (Query was removed in the modern
+// shim API) -- "read" is just another function routed through Invoke, and it
+// simply never calls PutState.
+func (t *SimpleChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	function, args := stub.GetFunctionAndParameters()
 	fmt.Println("invoke is running " + function)
 
 	// Handle different functions
 	if function == "init" {													//initialize the chaincode state, used as reset
-		return t.Init(stub, "init", args)
+		return t.Init(stub)
 	} else if function == "write" {
-		// Write uses two arguments, allowing you to pass in both the key and the value for the call to PutState. 
+		// Write uses two arguments, allowing you to pass in both the key and the value for the call to PutState.
 		// Basically, this function allows you to store any key/value pair you want into the blockchain ledger.
 		return t.write(stub, args)
-	}
-	fmt.Println("invoke did not find func: " + function)					//error
-
-	return nil, errors.New("Received unknown function invocation: " + function)
-}
-
-// Query is called whenever you query your chaincode's state. Queries do not result in blocks being added to the chain, 
-// and you cannot use functions like PutState inside of Query or any helper functions it calls. 
-// You will use Query to read the value of your chaincode state's key/value pairs.
-// Query is our entry point for queries
-func (t *SimpleChaincode) Query(stub shim.ChaincodeStubInterface, function string, args []string) ([]byte, error) {
-	fmt.Println("query is running " + function)
-
-	// Handle different functions
-	if function == "read" {											
+	} else if function == "read" {
+		// Read lets you look up the value stored for a given key without
+		// mutating the ledger.
 		return t.read(stub, args)
+	} else if function == "createAsset" {
+		return t.createAsset(stub, args)
+	} else if function == "updateAsset" {
+		return t.updateAsset(stub, args)
+	} else if function == "transferAsset" {
+		return t.transferAsset(stub, args)
+	} else if function == "deleteAsset" {
+		return t.deleteAsset(stub, args)
+	} else if function == "queryAssetsByOwner" {
+		return t.queryAssetsByOwner(stub, args)
+	} else if function == "getHistoryForKey" {
+		return t.getHistoryForKey(stub, args)
+	} else if function == "getStateByRange" {
+		return t.getStateByRange(stub, args)
+	} else if function == "invokeOther" {
+		return t.invokeOther(stub, args)
 	}
-	fmt.Println("query did not find func: " + function)						//error
+	fmt.Println("invoke did not find func: " + function)					//error
 
-	return nil, errors.New("Received unknown function query: " + function)
+	return shim.Error("Received unknown function invocation: " + function)
 }
 
-func (t *SimpleChaincode) write(stub shim.ChaincodeStubInterface, args []string)([]byte, error) {
+func (t *SimpleChaincode) write(stub shim.ChaincodeStubInterface, args []string) pb.Response {
 	var key, value string
 	var err error
 	fmt.Println("running write()")
 
 	if len(args) != 2 {
-		return nil, errors.New("Incorrect number of arguments. Expecting 2. name of the key and value to set")
+		return shim.Error("Incorrect number of arguments. Expecting 2. name of the key and value to set")
+	}
+
+	if err := authorize(stub, "write"); err != nil {
+		return shim.Error(err.Error())
 	}
 
 	key   = args[0]
 	value = args[1]
 	err   = stub.PutState(key, []byte(value))
 	if err != nil {
-		return nil, err
+		return shim.Error(err.Error())
 	}
-	return nil, nil
+	return shim.Success(nil)
 }
 
-func (t *SimpleChaincode) read(stub shim.ChaincodeStubInterface, args []string) ([]byte, error){
-	var key, jsonResp string
+func (t *SimpleChaincode) read(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	var key string
 	var err error
 
 	if len(args) != 1 {
-		return nil, errors.New("Incorrect number of arguments. Expecting name of the key to query")
+		return shim.Error("Incorrect number of arguments. Expecting name of the key to query")
 	}
 
 	key = args[0]
-	//  While PutState allows you to set a key/value pair, 
+	//  While PutState allows you to set a key/value pair,
 	//  GetState lets you read the value for a previously written key.
 	valAsbytes, err := stub.GetState(key)
 
 	if err != nil {
-		jsonResp = "{\"Error\":\"Failed to get state for " + key + "\"}"
-		return nil, errors.New(jsonResp)
+		jsonResp, marshalErr := json.Marshal(struct {
+			Error string `json:"Error"`
+		}{Error: "Failed to get state for " + key})
+		if marshalErr != nil {
+			return shim.Error(marshalErr.Error())
+		}
+		return shim.Error(string(jsonResp))
 	}
 
-	return valAsbytes, nil
+	return shim.Success(valAsbytes)
 }
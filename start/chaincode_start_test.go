@@ -0,0 +1,119 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+func checkInit(t *testing.T, stub *shim.MockStub, args [][]byte) {
+	res := stub.MockInit("1", args)
+	if res.Status != shim.OK {
+		t.Fatalf("Init failed: %s", res.Message)
+	}
+}
+
+func checkInitFailure(t *testing.T, stub *shim.MockStub, args [][]byte) {
+	res := stub.MockInit("1", args)
+	if res.Status == shim.OK {
+		t.Fatal("Init should have failed")
+	}
+}
+
+func checkInvoke(t *testing.T, stub *shim.MockStub, args [][]byte) []byte {
+	res := stub.MockInvoke("1", args)
+	if res.Status != shim.OK {
+		t.Fatalf("Invoke %v failed: %s", args, res.Message)
+	}
+	return res.Payload
+}
+
+func checkInvokeFailure(t *testing.T, stub *shim.MockStub, args [][]byte) {
+	res := stub.MockInvoke("1", args)
+	if res.Status == shim.OK {
+		t.Fatalf("Invoke %v should have failed", args)
+	}
+}
+
+func TestInit(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	if string(stub.State["hello_world"]) != "hello" {
+		t.Fatalf("hello_world was not set, got %s", stub.State["hello_world"])
+	}
+}
+
+func TestInit_WrongArgs(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+
+	checkInitFailure(t, stub, [][]byte{[]byte("init")})
+	checkInitFailure(t, stub, [][]byte{[]byte("init"), []byte("hello"), []byte("world")})
+}
+
+func TestInvoke_Write(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("foo"), []byte("bar")})
+
+	if string(stub.State["foo"]) != "bar" {
+		t.Fatalf("foo was not written, got %s", stub.State["foo"])
+	}
+}
+
+func TestInvoke_Read(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	payload := checkInvoke(t, stub, [][]byte{[]byte("read"), []byte("hello_world")})
+	if string(payload) != "hello" {
+		t.Fatalf("expected hello_world to read back \"hello\", got %s", payload)
+	}
+}
+
+func TestInvoke_ReadMissingKey(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	// MockStub.GetState never returns an error for an absent key, it just
+	// returns a nil value -- so read() succeeds with an empty payload.
+	payload := checkInvoke(t, stub, [][]byte{[]byte("read"), []byte("does_not_exist")})
+	if payload != nil {
+		t.Fatalf("expected nil payload for missing key, got %s", payload)
+	}
+}
+
+func TestInvoke_ReadWrongArgs(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	// Unlike a missing key, a wrong argument count does go through read()'s
+	// error branch, genuinely exercising it.
+	checkInvokeFailure(t, stub, [][]byte{[]byte("read")})
+	checkInvokeFailure(t, stub, [][]byte{[]byte("read"), []byte("a"), []byte("b")})
+}
+
+func TestInvoke_UnknownFunction(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("bogus")})
+}
@@ -0,0 +1,75 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// mutatingFunctions are the names this chaincode dispatches to a handler
+// that calls PutState or DelState. Per the Fabric chaincode developer guide,
+// a cross-channel InvokeChaincode is read-only at the peer -- any write the
+// callee performs is silently dropped from the invoking transaction's
+// RWset -- so invokeOther rejects them outright instead of letting a caller
+// believe a cross-channel write took effect.
+var mutatingFunctions = map[string]bool{
+	"init":          true,
+	"initACL":       true,
+	"write":         true,
+	"createAsset":   true,
+	"updateAsset":   true,
+	"transferAsset": true,
+	"deleteAsset":   true,
+	"invokeOther":   true,
+}
+
+// invokeOther expects args: [chaincodeName, channel, fn, ...fnArgs] and
+// forwards the call to another chaincode via stub.InvokeChaincode, returning
+// its response payload as-is.
+//
+// When channel is empty, the call targets the same channel this chaincode is
+// executing on and may read and write state. When channel is set, the call
+// is cross-channel and therefore read-only: invokeOther rejects fn if it is
+// one of this chaincode's own mutating functions rather than relying on the
+// callee to reject it.
+func (t *SimpleChaincode) invokeOther(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) < 3 {
+		return shim.Error("Incorrect number of arguments. Expecting at least 3: chaincodeName, channel, fn, [fnArgs...]")
+	}
+
+	chaincodeName := args[0]
+	channel := args[1]
+	fn := args[2]
+	fnArgs := args[2:]
+
+	if channel != "" && mutatingFunctions[fn] {
+		return shim.Error("cross-channel invokeOther is read-only; " + fn + " is a mutating function")
+	}
+
+	ccArgs := make([][]byte, len(fnArgs))
+	for i, a := range fnArgs {
+		ccArgs[i] = []byte(a)
+	}
+
+	response := stub.InvokeChaincode(chaincodeName, ccArgs, channel)
+	if response.Status != shim.OK {
+		return shim.Error(response.Message)
+	}
+
+	return shim.Success(response.Payload)
+}
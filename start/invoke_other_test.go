@@ -0,0 +1,72 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+)
+
+func TestInvokeOtherSameChannel(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	other := shim.NewMockStub("other", new(SimpleChaincode))
+	checkInit(t, other, [][]byte{[]byte("init"), []byte("hello")})
+	stub.MockPeerChaincode("other", other)
+
+	checkInvoke(t, stub, [][]byte{[]byte("invokeOther"), []byte("other"), []byte(""), []byte("write"), []byte("foo"), []byte("bar")})
+
+	if string(other.State["foo"]) != "bar" {
+		t.Fatalf("expected same-channel invokeOther to write through to other, got %s", other.State["foo"])
+	}
+}
+
+func TestInvokeOtherCrossChannel(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	other := shim.NewMockStub("other", new(SimpleChaincode))
+	checkInit(t, other, [][]byte{[]byte("init"), []byte("hello")})
+	// MockStub.InvokeChaincode looks up "chaincodeName/channel", not
+	// "channel/chaincodeName" -- register under that key order.
+	stub.MockPeerChaincode("other/otherchannel", other)
+
+	payload := checkInvoke(t, stub, [][]byte{[]byte("invokeOther"), []byte("other"), []byte("otherchannel"), []byte("read"), []byte("hello_world")})
+
+	if string(payload) != "hello" {
+		t.Fatalf("expected cross-channel read to return hello, got %s", payload)
+	}
+}
+
+func TestInvokeOtherCrossChannelWriteRejected(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	other := shim.NewMockStub("other", new(SimpleChaincode))
+	checkInit(t, other, [][]byte{[]byte("init"), []byte("hello")})
+	// MockStub.InvokeChaincode looks up "chaincodeName/channel", not
+	// "channel/chaincodeName" -- register under that key order.
+	stub.MockPeerChaincode("other/otherchannel", other)
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("invokeOther"), []byte("other"), []byte("otherchannel"), []byte("write"), []byte("foo"), []byte("bar")})
+
+	if string(other.State["foo"]) != "" {
+		t.Fatalf("expected cross-channel write to be rejected before reaching other, got %s", other.State["foo"])
+	}
+}
@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	pb "github.com/hyperledger/fabric/protos/peer"
+)
+
+// historyEntry is one entry of the JSON array returned by getHistoryForKey --
+// a flattened view of the ledger's KeyModification records.
+type historyEntry struct {
+	TxId      string `json:"txId"`
+	Value     string `json:"value"`
+	Timestamp int64  `json:"timestamp"`
+	IsDelete  bool   `json:"isDelete"`
+}
+
+// keyValue is one entry of the JSON array returned by getStateByRange.
+type keyValue struct {
+	Key    string `json:"key"`
+	Record string `json:"record"`
+}
+
+// rangeResult wraps a getStateByRange response; Bookmark is only populated
+// when the call was paginated.
+type rangeResult struct {
+	Results  []keyValue `json:"results"`
+	Bookmark string     `json:"bookmark,omitempty"`
+}
+
+// getHistoryForKey expects args: [key] and returns a JSON array of every
+// KeyModification recorded for key, oldest first.
+func (t *SimpleChaincode) getHistoryForKey(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 1 {
+		return shim.Error("Incorrect number of arguments. Expecting 1: key")
+	}
+	key := args[0]
+
+	iterator, err := stub.GetHistoryForKey(key)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer iterator.Close()
+
+	history, err := collectHistory(iterator)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	historyBytes, err := json.Marshal(history)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(historyBytes)
+}
+
+// getStateByRange expects args: [startKey, endKey] and optionally
+// [pageSize, bookmark] to page through the range via
+// GetStateByRangeWithPagination instead of returning everything at once.
+func (t *SimpleChaincode) getStateByRange(stub shim.ChaincodeStubInterface, args []string) pb.Response {
+	if len(args) != 2 && len(args) != 4 {
+		return shim.Error("Incorrect number of arguments. Expecting 2: startKey, endKey, or 4 with pageSize, bookmark")
+	}
+	startKey := args[0]
+	endKey := args[1]
+
+	if len(args) == 2 {
+		iterator, err := stub.GetStateByRange(startKey, endKey)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		defer iterator.Close()
+
+		results, err := collectRange(iterator)
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+
+		resultBytes, err := json.Marshal(rangeResult{Results: results})
+		if err != nil {
+			return shim.Error(err.Error())
+		}
+		return shim.Success(resultBytes)
+	}
+
+	pageSize, err := strconv.ParseInt(args[2], 10, 32)
+	if err != nil {
+		return shim.Error("pageSize must be an integer: " + err.Error())
+	}
+	bookmark := args[3]
+
+	iterator, metadata, err := stub.GetStateByRangeWithPagination(startKey, endKey, int32(pageSize), bookmark)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	defer iterator.Close()
+
+	results, err := collectRange(iterator)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	resultBytes, err := json.Marshal(rangeResult{Results: results, Bookmark: metadata.GetBookmark()})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+	return shim.Success(resultBytes)
+}
+
+// collectRange drains a range iterator into a slice of keyValue pairs.
+func collectRange(iterator shim.StateQueryIteratorInterface) ([]keyValue, error) {
+	results := []keyValue{}
+	for iterator.HasNext() {
+		kv, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, keyValue{Key: kv.Key, Record: string(kv.Value)})
+	}
+	return results, nil
+}
+
+// collectHistory drains a history iterator into a slice of historyEntry
+// records, oldest first.
+func collectHistory(iterator shim.HistoryQueryIteratorInterface) ([]historyEntry, error) {
+	history := []historyEntry{}
+	for iterator.HasNext() {
+		mod, err := iterator.Next()
+		if err != nil {
+			return nil, err
+		}
+
+		history = append(history, historyEntry{
+			TxId:      mod.TxId,
+			Value:     string(mod.Value),
+			Timestamp: mod.Timestamp.GetSeconds(),
+			IsDelete:  mod.IsDelete,
+		})
+	}
+	return history, nil
+}
@@ -0,0 +1,129 @@
+/*
+Copyright IBM Corp 2016 All Rights Reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+		 http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+)
+
+// fakeHistoryIterator is a hand-rolled shim.HistoryQueryIteratorInterface:
+// the shim's own MockStub.GetHistoryForKey unconditionally returns
+// "not implemented" (there is no history DB behind the mock), so
+// collectHistory's JSON-shaping logic is exercised against this instead.
+type fakeHistoryIterator struct {
+	mods []*queryresult.KeyModification
+	i    int
+}
+
+func (f *fakeHistoryIterator) HasNext() bool { return f.i < len(f.mods) }
+
+func (f *fakeHistoryIterator) Next() (*queryresult.KeyModification, error) {
+	mod := f.mods[f.i]
+	f.i++
+	return mod, nil
+}
+
+func (f *fakeHistoryIterator) Close() error { return nil }
+
+func TestCollectHistory(t *testing.T) {
+	iterator := &fakeHistoryIterator{mods: []*queryresult.KeyModification{
+		{TxId: "tx1", Value: []byte("bar"), Timestamp: &timestamp.Timestamp{Seconds: 100}, IsDelete: false},
+		{TxId: "tx2", Value: []byte("baz"), Timestamp: &timestamp.Timestamp{Seconds: 200}, IsDelete: false},
+	}}
+
+	history, err := collectHistory(iterator)
+	if err != nil {
+		t.Fatalf("collectHistory failed: %s", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("expected 2 history entries, got %d", len(history))
+	}
+	if history[len(history)-1].Value != "baz" {
+		t.Fatalf("expected most recent value to be baz, got %s", history[len(history)-1].Value)
+	}
+
+	historyBytes, err := json.Marshal(history)
+	if err != nil {
+		t.Fatalf("could not marshal history: %s", err)
+	}
+	var roundTripped []historyEntry
+	if err := json.Unmarshal(historyBytes, &roundTripped); err != nil {
+		t.Fatalf("could not round-trip history JSON: %s", err)
+	}
+	if roundTripped[0].TxId != "tx1" || roundTripped[0].Timestamp != 100 {
+		t.Fatalf("unexpected round-tripped entry: %+v", roundTripped[0])
+	}
+}
+
+func TestGetStateByRange(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("a"), []byte("1")})
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("b"), []byte("2")})
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("c"), []byte("3")})
+
+	payload := checkInvoke(t, stub, [][]byte{[]byte("getStateByRange"), []byte("a"), []byte("c")})
+
+	var result rangeResult
+	if err := json.Unmarshal(payload, &result); err != nil {
+		t.Fatalf("could not unmarshal range result: %s", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("expected 2 keys in range [a, c), got %d", len(result.Results))
+	}
+}
+
+// TestGetStateByRangeWithPagination_MockStubLimitation documents that the
+// shim's MockStub.GetStateByRangeWithPagination unconditionally returns
+// "not implemented" (no CouchDB behind the mock), so invoking the paginated
+// path can only ever exercise error propagation here, not a successful
+// paginated response. The JSON-shaping logic it would use on a real peer is
+// covered directly by TestRangeResultJSONShape below.
+func TestGetStateByRangeWithPagination_MockStubLimitation(t *testing.T) {
+	stub := shim.NewMockStub("simple", new(SimpleChaincode))
+	checkInit(t, stub, [][]byte{[]byte("init"), []byte("hello")})
+
+	checkInvoke(t, stub, [][]byte{[]byte("write"), []byte("a"), []byte("1")})
+
+	checkInvokeFailure(t, stub, [][]byte{[]byte("getStateByRange"), []byte("a"), []byte("d"), []byte("1"), []byte("")})
+}
+
+func TestRangeResultJSONShape(t *testing.T) {
+	result := rangeResult{
+		Results:  []keyValue{{Key: "a", Record: "1"}},
+		Bookmark: "bm",
+	}
+
+	resultBytes, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("could not marshal rangeResult: %s", err)
+	}
+
+	var roundTripped rangeResult
+	if err := json.Unmarshal(resultBytes, &roundTripped); err != nil {
+		t.Fatalf("could not round-trip rangeResult JSON: %s", err)
+	}
+	if len(roundTripped.Results) != 1 || roundTripped.Results[0].Key != "a" || roundTripped.Bookmark != "bm" {
+		t.Fatalf("unexpected round-tripped rangeResult: %+v", roundTripped)
+	}
+}